@@ -0,0 +1,139 @@
+package mqttclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/google/uuid"
+)
+
+// 内置编解码器对应的 MIME 内容类型
+const (
+	ContentTypeJSON = "application/json"
+	ContentTypeCBOR = "application/cbor"
+	ContentTypeRaw  = "application/octet-stream"
+)
+
+// Codec 定义事件负载的编解码方式。Encode 返回负载及其内容类型，
+// Decode 根据消息携带的内容类型将负载还原为 dtos.Event。
+// 使用方可通过 RegisterCodec 注册自定义实现（如 Protobuf）。
+type Codec interface {
+	Encode(event dtos.Event) ([]byte, string, error)
+	Decode(payload []byte, contentType string) (dtos.Event, error)
+}
+
+// jsonCodec 是默认的 JSON 编解码实现
+type jsonCodec struct{}
+
+func (jsonCodec) Encode(event dtos.Event) ([]byte, string, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, "", fmt.Errorf("序列化事件失败: %v", err)
+	}
+	return payload, ContentTypeJSON, nil
+}
+
+func (jsonCodec) Decode(payload []byte, _ string) (dtos.Event, error) {
+	var event dtos.Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return dtos.Event{}, fmt.Errorf("解析事件失败: %v", err)
+	}
+	return event, nil
+}
+
+// cborCodec 使用 CBOR 编解码，适配上报二进制读数的 EdgeX 设备服务
+type cborCodec struct{}
+
+func (cborCodec) Encode(event dtos.Event) ([]byte, string, error) {
+	payload, err := cbor.Marshal(event)
+	if err != nil {
+		return nil, "", fmt.Errorf("CBOR 序列化事件失败: %v", err)
+	}
+	return payload, ContentTypeCBOR, nil
+}
+
+func (cborCodec) Decode(payload []byte, _ string) (dtos.Event, error) {
+	var event dtos.Event
+	if err := cbor.Unmarshal(payload, &event); err != nil {
+		return dtos.Event{}, fmt.Errorf("CBOR 解析事件失败: %v", err)
+	}
+	return event, nil
+}
+
+// rawCodec 透传任意字节负载，将其包装为一个合成的 dtos.Event，
+// 用于接入不产出 EdgeX Event 信封的普通传感器
+type rawCodec struct {
+	deviceName   string
+	resourceName string
+}
+
+func (rc rawCodec) Encode(event dtos.Event) ([]byte, string, error) {
+	if len(event.Readings) == 0 {
+		return nil, "", fmt.Errorf("raw 编码要求事件至少包含一个 Reading")
+	}
+	return []byte(event.Readings[0].SimpleReading.Value), ContentTypeRaw, nil
+}
+
+func (rc rawCodec) Decode(payload []byte, _ string) (dtos.Event, error) {
+	now := time.Now().UnixNano()
+	reading := dtos.BaseReading{
+		Id:           uuid.New().String(),
+		DeviceName:   rc.deviceName,
+		ResourceName: rc.resourceName,
+		Origin:       now,
+		ValueType:    "String",
+		SimpleReading: dtos.SimpleReading{
+			Value: string(payload),
+		},
+	}
+	return dtos.Event{
+		Id:         uuid.New().String(),
+		DeviceName: rc.deviceName,
+		Origin:     now,
+		Readings:   []dtos.BaseReading{reading},
+	}, nil
+}
+
+// defaultCodecs 返回客户端初始内置的编解码器注册表
+func defaultCodecs(clientID string) map[string]Codec {
+	return map[string]Codec{
+		ContentTypeJSON: jsonCodec{},
+		ContentTypeCBOR: cborCodec{},
+		ContentTypeRaw:  rawCodec{deviceName: clientID, resourceName: "raw"},
+	}
+}
+
+// contentTypeForCodec 将配置中的简写编码名称（json/cbor/raw）转换为对应的内容类型
+func contentTypeForCodec(name string) (string, error) {
+	switch name {
+	case "", "json":
+		return ContentTypeJSON, nil
+	case "cbor":
+		return ContentTypeCBOR, nil
+	case "raw":
+		return ContentTypeRaw, nil
+	default:
+		return "", fmt.Errorf("不支持的编码类型: %s", name)
+	}
+}
+
+// RegisterCodec 注册或覆盖一个内容类型对应的编解码器
+func (c *MQTTClient) RegisterCodec(contentType string, codec Codec) {
+	c.codecsMu.Lock()
+	defer c.codecsMu.Unlock()
+	c.codecs[contentType] = codec
+}
+
+// codecFor 查找内容类型对应的编解码器
+func (c *MQTTClient) codecFor(contentType string) (Codec, error) {
+	c.codecsMu.RLock()
+	defer c.codecsMu.RUnlock()
+	codec, ok := c.codecs[contentType]
+	if !ok {
+		return nil, fmt.Errorf("未注册内容类型 %s 对应的编解码器", contentType)
+	}
+	return codec, nil
+}
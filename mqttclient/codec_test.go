@@ -0,0 +1,134 @@
+package mqttclient
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+)
+
+func sampleEvent() dtos.Event {
+	return dtos.Event{
+		Id:          "11111111-1111-1111-1111-111111111111",
+		DeviceName:  "TestDevice",
+		ProfileName: "TestProfile",
+		SourceName:  "TestSource",
+		Origin:      1,
+		Readings: []dtos.BaseReading{
+			{
+				Id:           "22222222-2222-2222-2222-222222222222",
+				DeviceName:   "TestDevice",
+				ResourceName: "TestResource",
+				ProfileName:  "TestProfile",
+				Origin:       1,
+				ValueType:    "String",
+				SimpleReading: dtos.SimpleReading{
+					Value: "hello",
+				},
+			},
+		},
+	}
+}
+
+func TestJSONCodecRoundTrip(t *testing.T) {
+	event := sampleEvent()
+	payload, contentType, err := jsonCodec{}.Encode(event)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if contentType != ContentTypeJSON {
+		t.Fatalf("Encode() contentType = %q, want %q", contentType, ContentTypeJSON)
+	}
+
+	decoded, err := jsonCodec{}.Decode(payload, contentType)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if decoded.Id != event.Id || decoded.Readings[0].SimpleReading.Value != "hello" {
+		t.Errorf("Decode() = %+v, want round-trip of %+v", decoded, event)
+	}
+}
+
+func TestCBORCodecRoundTrip(t *testing.T) {
+	event := sampleEvent()
+	payload, contentType, err := cborCodec{}.Encode(event)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if contentType != ContentTypeCBOR {
+		t.Fatalf("Encode() contentType = %q, want %q", contentType, ContentTypeCBOR)
+	}
+
+	decoded, err := cborCodec{}.Decode(payload, contentType)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if decoded.Id != event.Id || decoded.Readings[0].SimpleReading.Value != "hello" {
+		t.Errorf("Decode() = %+v, want round-trip of %+v", decoded, event)
+	}
+}
+
+func TestRawCodecRoundTrip(t *testing.T) {
+	rc := rawCodec{deviceName: "TestDevice", resourceName: "raw"}
+	event := sampleEvent()
+
+	payload, contentType, err := rc.Encode(event)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if contentType != ContentTypeRaw {
+		t.Fatalf("Encode() contentType = %q, want %q", contentType, ContentTypeRaw)
+	}
+	if string(payload) != "hello" {
+		t.Fatalf("Encode() payload = %q, want %q", payload, "hello")
+	}
+
+	decoded, err := rc.Decode(payload, contentType)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if decoded.DeviceName != "TestDevice" || decoded.Readings[0].SimpleReading.Value != "hello" {
+		t.Errorf("Decode() = %+v, want device %q with reading %q", decoded, "TestDevice", "hello")
+	}
+}
+
+// TestRawCodecEncodeEmptyReadings 验证 raw 编码在事件不含任何 Reading 时报错，
+// 而不是越界访问 Readings[0]
+func TestRawCodecEncodeEmptyReadings(t *testing.T) {
+	rc := rawCodec{deviceName: "TestDevice", resourceName: "raw"}
+	event := sampleEvent()
+	event.Readings = nil
+
+	if _, _, err := rc.Encode(event); err == nil {
+		t.Fatal("Encode() with no readings: error = nil, want error")
+	}
+}
+
+func TestContentTypeForCodec(t *testing.T) {
+	cases := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", ContentTypeJSON, false},
+		{"json", ContentTypeJSON, false},
+		{"cbor", ContentTypeCBOR, false},
+		{"raw", ContentTypeRaw, false},
+		{"protobuf", "", true},
+	}
+
+	for _, tc := range cases {
+		got, err := contentTypeForCodec(tc.name)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("contentTypeForCodec(%q): error = nil, want error", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("contentTypeForCodec(%q) error = %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Errorf("contentTypeForCodec(%q) = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
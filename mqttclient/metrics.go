@@ -0,0 +1,122 @@
+package mqttclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-messaging/v3/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsSet 聚合本客户端对外暴露的 Prometheus 指标。每个 MQTTClient 持有独立的
+// Registry，避免同一进程内创建多个客户端实例时发生重复注册。
+type metricsSet struct {
+	registry *prometheus.Registry
+
+	messagesReceivedTotal  *prometheus.CounterVec
+	messagesPublishedTotal *prometheus.CounterVec
+	publishLatencySeconds  prometheus.Histogram
+	reconnectsTotal        prometheus.Counter
+	offlineQueueDepth      prometheus.Gauge
+	handlerErrorsTotal     prometheus.Counter
+	brokerStats            *prometheus.GaugeVec
+}
+
+// newMetricsSet 构建并注册本客户端用到的全部指标
+func newMetricsSet() *metricsSet {
+	m := &metricsSet{
+		registry: prometheus.NewRegistry(),
+		messagesReceivedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mqtt_messages_received_total",
+			Help: "已成功接收并分发的消息数量",
+		}, []string{"topic"}),
+		messagesPublishedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mqtt_messages_published_total",
+			Help: "发布消息的数量，按结果（success/error/queued）区分",
+		}, []string{"topic", "result"}),
+		publishLatencySeconds: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name: "mqtt_publish_latency_seconds",
+			Help: "单次发布事件的耗时",
+		}),
+		reconnectsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "mqtt_reconnects_total",
+			Help: "成功完成的重连次数",
+		}),
+		offlineQueueDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "mqtt_offline_queue_depth",
+			Help: "离线消息队列当前堆积的消息数",
+		}),
+		handlerErrorsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "mqtt_handler_errors_total",
+			Help: "订阅消息查找编解码器或解码失败的次数",
+		}),
+		brokerStats: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "mqtt_broker_stats",
+			Help: "从 $SYS/broker/# 桥接的 broker 运行指标（load/clients/connections 等）",
+		}, []string{"metric"}),
+	}
+
+	m.registry.MustRegister(
+		m.messagesReceivedTotal,
+		m.messagesPublishedTotal,
+		m.publishLatencySeconds,
+		m.reconnectsTotal,
+		m.offlineQueueDepth,
+		m.handlerErrorsTotal,
+		m.brokerStats,
+	)
+	return m
+}
+
+// MetricsHandler 返回可挂载到任意 HTTP 服务的 Prometheus 指标处理器
+func (c *MQTTClient) MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(c.metrics.registry, promhttp.HandlerOpts{})
+}
+
+// startMetricsServer 在 [metrics] enabled=true 时启动独立的 HTTP 服务暴露 /metrics
+func (c *MQTTClient) startMetricsServer() {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", c.MetricsHandler())
+	server := &http.Server{Addr: c.config.Metrics.Listen, Handler: mux}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.logger.Info(fmt.Sprintf("指标服务已启动: %s/metrics", c.config.Metrics.Listen))
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			c.logger.Error(fmt.Sprintf("指标服务异常退出: %v", err))
+		}
+	}()
+
+	go func() {
+		<-c.stopCh
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+	}()
+}
+
+// startSysBridge 订阅 $SYS/broker/# 并将 broker 运行状态写入 brokerStats 指标，
+// 做法与 Paho 示例中的 $SYS 路由类似
+func (c *MQTTClient) startSysBridge() {
+	if _, err := c.subscribeRaw("$SYS/broker/#", c.handleSysMessage); err != nil {
+		c.logger.Error(fmt.Sprintf("订阅 $SYS/broker/# 失败: %v", err))
+	}
+}
+
+// handleSysMessage 解析形如 $SYS/broker/load/1min、$SYS/broker/clients/connected
+// 的 broker 状态主题，将数值部分更新到对应的 brokerStats 指标
+func (c *MQTTClient) handleSysMessage(msg types.MessageEnvelope) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(string(msg.Payload)), 64)
+	if err != nil {
+		return
+	}
+
+	metric := strings.TrimPrefix(msg.ReceivedTopic, "$SYS/broker/")
+	c.metrics.brokerStats.WithLabelValues(metric).Set(value)
+}
@@ -1,7 +1,6 @@
 package mqttclient
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
@@ -32,22 +31,91 @@ type Config struct {
 	Publish   bool   `toml:"publish"`
 	Interval  int    `toml:"publish_interval"` // 发布间隔（秒）
 	LogLevel  string `toml:"log_level"`
+	Reconnect struct {
+		InitialInterval    int     `toml:"initial_interval"`     // 初始重连间隔（秒）
+		MaxInterval        int     `toml:"max_interval"`         // 最大重连间隔（秒）
+		MaxAttempts        int     `toml:"max_attempts"`         // 最大重试次数，0 表示不限制
+		Jitter             float64 `toml:"jitter"`               // 抖动比例（0~1）
+		OfflineQueueSize   int     `toml:"offline_queue_size"`   // 离线消息队列容量
+		OfflineQueuePolicy string  `toml:"offline_queue_policy"` // drop-oldest 或 block
+	} `toml:"reconnect"`
+	TLS struct {
+		CAFile             string `toml:"ca_file"`
+		CertFile           string `toml:"cert_file"`
+		KeyFile            string `toml:"key_file"`
+		InsecureSkipVerify bool   `toml:"insecure_skip_verify"`
+		ServerName         string `toml:"server_name"`
+		MinVersion         string `toml:"min_version"` // 启用 TLS 时任何非空值都会被 applyTLSOptions 拒绝
+	} `toml:"tls"`
+	// Subscriptions 支持同时订阅多个主题，各自绑定独立的处理函数
+	Subscriptions []SubscriptionConfig `toml:"subscriptions"`
+	// Codec 决定 PublishEvent 默认使用的编码：json（默认）、cbor 或 raw
+	Codec   string `toml:"codec"`
+	Metrics struct {
+		Enabled bool   `toml:"enabled"`
+		Listen  string `toml:"listen"`
+	} `toml:"metrics"`
+	// Will 配置遗嘱消息：客户端异常掉线时由 broker 代为发布，用于下游追踪客户端存活状态
+	Will struct {
+		Topic    string `toml:"topic"`
+		Payload  string `toml:"payload"`
+		QoS      byte   `toml:"qos"`
+		Retained bool   `toml:"retained"`
+	} `toml:"will"`
 	EnvPrefix string // 环境变量前缀
 }
 
+// SubscriptionConfig 对应一个 [[subscriptions]] 配置块。没有按主题单独配置 QoS 的字段：
+// go-mod-messaging MQTT provider 的 Subscribe() 对每个主题都固定沿用 [will] 配置的
+// WillQos（与 Publish 对 WillQos/WillRetained 的处理一致），没有按订阅区分 QoS 的余地
+type SubscriptionConfig struct {
+	Topic string `toml:"topic"`
+}
+
 // MessageHandler 定义消息处理回调函数
 type MessageHandler func(topic string, event dtos.Event)
 
+// MqttConnListener 定义连接生命周期事件回调，使用方可通过 AddConnListener 注册
+type MqttConnListener interface {
+	OnConnected(client *MQTTClient)
+	OnDisconnecting(client *MQTTClient)
+	OnDisconnected(client *MQTTClient, err error)
+	OnLostConn(client *MQTTClient, err error)
+	OnReconnecting(client *MQTTClient, attempt int)
+}
+
+// pendingPublish 表示离线期间排队等待重连后发送的消息
+type pendingPublish struct {
+	envelope types.MessageEnvelope
+	topic    string
+}
+
 // MQTTClient 封装的 MQTT 客户端
 type MQTTClient struct {
 	config      Config
 	logger      logger.LoggingClient
 	messageBus  messaging.MessageClient
-	messages    chan types.MessageEnvelope
 	messageErrs chan error
 	stopCh      chan struct{}
 	wg          sync.WaitGroup
 	handler     MessageHandler
+
+	connMu    sync.RWMutex
+	connected bool
+	listeners []MqttConnListener
+
+	offlineQueue chan pendingPublish
+	queueMu      sync.Mutex
+
+	subsMu        sync.Mutex
+	subscriptions map[string]*subscription
+	router        *topicRouter
+
+	codecsMu           sync.RWMutex
+	codecs             map[string]Codec
+	defaultContentType string
+
+	metrics *metricsSet
 }
 
 // NewMQTTClient 创建新的 MQTT 客户端
@@ -70,8 +138,17 @@ func NewMQTTClient(configPath string, handler MessageHandler) (*MQTTClient, erro
 		LogLevel:  "INFO",
 		Publish:   false,
 		Interval:  10,
+		Codec:     "json",
 		EnvPrefix: "EDGEX_",
 	}
+	cfg.Metrics.Enabled = false
+	cfg.Metrics.Listen = ":9090"
+	cfg.Reconnect.InitialInterval = 1
+	cfg.Reconnect.MaxInterval = 30
+	cfg.Reconnect.MaxAttempts = 0
+	cfg.Reconnect.Jitter = 0.2
+	cfg.Reconnect.OfflineQueueSize = 100
+	cfg.Reconnect.OfflineQueuePolicy = "drop-oldest"
 
 	// 加载配置文件
 	if configPath != "" {
@@ -105,6 +182,12 @@ func NewMQTTClient(configPath string, handler MessageHandler) (*MQTTClient, erro
 		busConfig.Optional["Password"] = cfg.Password
 	}
 
+	if err := applyTLSOptions(&cfg, &busConfig); err != nil {
+		return nil, fmt.Errorf("加载 TLS 配置失败: %v", err)
+	}
+
+	applyWillOptions(&cfg, &busConfig)
+
 	// 创建消息客户端
 	messageBus, err := messaging.NewMessageClient(busConfig)
 	if err != nil {
@@ -112,18 +195,60 @@ func NewMQTTClient(configPath string, handler MessageHandler) (*MQTTClient, erro
 	}
 
 	client := &MQTTClient{
-		config:      cfg,
-		logger:      lc,
-		messageBus:  messageBus,
-		messages:    make(chan types.MessageEnvelope),
-		messageErrs: make(chan error),
-		stopCh:      make(chan struct{}),
-		handler:     handler,
+		config:        cfg,
+		logger:        lc,
+		messageBus:    messageBus,
+		messageErrs:   make(chan error),
+		stopCh:        make(chan struct{}),
+		handler:       handler,
+		offlineQueue:  make(chan pendingPublish, cfg.Reconnect.OfflineQueueSize),
+		subscriptions: make(map[string]*subscription),
+		router:        newTopicRouter(),
+		codecs:        defaultCodecs(cfg.ClientID),
+		metrics:       newMetricsSet(),
+	}
+
+	contentType, err := contentTypeForCodec(cfg.Codec)
+	if err != nil {
+		return nil, err
 	}
+	client.defaultContentType = contentType
 
 	return client, nil
 }
 
+// AddConnListener 注册一个连接生命周期事件监听器
+func (c *MQTTClient) AddConnListener(listener MqttConnListener) {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+	c.listeners = append(c.listeners, listener)
+}
+
+// notifyListeners 依次触发所有已注册监听器的回调
+func (c *MQTTClient) notifyListeners(fn func(MqttConnListener)) {
+	c.connMu.RLock()
+	listeners := make([]MqttConnListener, len(c.listeners))
+	copy(listeners, c.listeners)
+	c.connMu.RUnlock()
+	for _, l := range listeners {
+		fn(l)
+	}
+}
+
+// setConnected 更新连接状态
+func (c *MQTTClient) setConnected(connected bool) {
+	c.connMu.Lock()
+	c.connected = connected
+	c.connMu.Unlock()
+}
+
+// isConnected 返回当前连接状态
+func (c *MQTTClient) isConnected() bool {
+	c.connMu.RLock()
+	defer c.connMu.RUnlock()
+	return c.connected
+}
+
 // loadEnvOverrides 从环境变量加载配置
 func loadEnvOverrides(cfg *Config) {
 	if v := os.Getenv(cfg.EnvPrefix + "BROKER_HOST"); v != "" {
@@ -163,6 +288,65 @@ func loadEnvOverrides(cfg *Config) {
 	if v := os.Getenv(cfg.EnvPrefix + "LOG_LEVEL"); v != "" {
 		cfg.LogLevel = v
 	}
+	if v := os.Getenv(cfg.EnvPrefix + "RECONNECT_INITIAL_INTERVAL"); v != "" {
+		if n, err := fmt.Sscanf(v, "%d", &cfg.Reconnect.InitialInterval); err == nil && n == 1 {
+			cfg.Reconnect.InitialInterval = cfg.Reconnect.InitialInterval
+		}
+	}
+	if v := os.Getenv(cfg.EnvPrefix + "RECONNECT_MAX_INTERVAL"); v != "" {
+		if n, err := fmt.Sscanf(v, "%d", &cfg.Reconnect.MaxInterval); err == nil && n == 1 {
+			cfg.Reconnect.MaxInterval = cfg.Reconnect.MaxInterval
+		}
+	}
+	if v := os.Getenv(cfg.EnvPrefix + "RECONNECT_MAX_ATTEMPTS"); v != "" {
+		if n, err := fmt.Sscanf(v, "%d", &cfg.Reconnect.MaxAttempts); err == nil && n == 1 {
+			cfg.Reconnect.MaxAttempts = cfg.Reconnect.MaxAttempts
+		}
+	}
+	if v := os.Getenv(cfg.EnvPrefix + "OFFLINE_QUEUE_SIZE"); v != "" {
+		if n, err := fmt.Sscanf(v, "%d", &cfg.Reconnect.OfflineQueueSize); err == nil && n == 1 {
+			cfg.Reconnect.OfflineQueueSize = cfg.Reconnect.OfflineQueueSize
+		}
+	}
+	if v := os.Getenv(cfg.EnvPrefix + "OFFLINE_QUEUE_POLICY"); v != "" {
+		cfg.Reconnect.OfflineQueuePolicy = v
+	}
+	if v := os.Getenv(cfg.EnvPrefix + "TLS_CA_FILE"); v != "" {
+		cfg.TLS.CAFile = v
+	}
+	if v := os.Getenv(cfg.EnvPrefix + "TLS_CERT_FILE"); v != "" {
+		cfg.TLS.CertFile = v
+	}
+	if v := os.Getenv(cfg.EnvPrefix + "TLS_KEY_FILE"); v != "" {
+		cfg.TLS.KeyFile = v
+	}
+	if v := os.Getenv(cfg.EnvPrefix + "TLS_INSECURE_SKIP_VERIFY"); v == "true" {
+		cfg.TLS.InsecureSkipVerify = true
+	}
+	if v := os.Getenv(cfg.EnvPrefix + "TLS_SERVER_NAME"); v != "" {
+		cfg.TLS.ServerName = v
+	}
+	if v := os.Getenv(cfg.EnvPrefix + "TLS_MIN_VERSION"); v != "" {
+		cfg.TLS.MinVersion = v
+	}
+	if v := os.Getenv(cfg.EnvPrefix + "CODEC"); v != "" {
+		cfg.Codec = v
+	}
+	if v := os.Getenv(cfg.EnvPrefix + "METRICS_ENABLED"); v == "true" {
+		cfg.Metrics.Enabled = true
+	}
+	if v := os.Getenv(cfg.EnvPrefix + "METRICS_LISTEN"); v != "" {
+		cfg.Metrics.Listen = v
+	}
+	if v := os.Getenv(cfg.EnvPrefix + "WILL_TOPIC"); v != "" {
+		cfg.Will.Topic = v
+	}
+	if v := os.Getenv(cfg.EnvPrefix + "WILL_PAYLOAD"); v != "" {
+		cfg.Will.Payload = v
+	}
+	if v := os.Getenv(cfg.EnvPrefix + "WILL_RETAINED"); v == "true" {
+		cfg.Will.Retained = true
+	}
 }
 
 // Start 启动客户端
@@ -173,16 +357,28 @@ func (c *MQTTClient) Start() error {
 	}
 	c.logger.Info("成功连接到MQTT消息总线")
 
-	// 订阅主题
-	topics := []types.TopicChannel{{Topic: c.config.Topic, Messages: c.messages}}
-	if err := c.messageBus.Subscribe(topics, c.messageErrs); err != nil {
-		return fmt.Errorf("订阅消息失败: %v", err)
+	// 订阅配置中的主题（支持 [[subscriptions]] 多主题或旧版单主题）
+	if err := c.subscribeConfigured(); err != nil {
+		return err
 	}
-	c.logger.Info(fmt.Sprintf("成功订阅主题: %s", c.config.Topic))
 
-	// 启动消息处理
+	c.setConnected(true)
+	c.notifyListeners(func(l MqttConnListener) { l.OnConnected(c) })
+	c.publishBirthMessage()
+
+	// 启动错误监听
 	c.wg.Add(1)
-	go c.handleMessages()
+	go c.handleErrors()
+
+	// 启动离线消息队列的排空处理
+	c.wg.Add(1)
+	go c.drainOfflineQueue()
+
+	// 启用指标时，暴露 /metrics 并桥接 $SYS/broker/# 运行状态
+	if c.config.Metrics.Enabled {
+		c.startMetricsServer()
+		c.startSysBridge()
+	}
 
 	// 启动发布（如果启用）
 	if c.config.Publish {
@@ -196,31 +392,14 @@ func (c *MQTTClient) Start() error {
 	return nil
 }
 
-// handleMessages 处理接收到的消息
-func (c *MQTTClient) handleMessages() {
+// handleErrors 监听消息总线上报的传输错误并触发重连
+func (c *MQTTClient) handleErrors() {
 	defer c.wg.Done()
 	for {
 		select {
 		case err := <-c.messageErrs:
 			c.logger.Error(fmt.Sprintf("接收消息错误: %v", err))
-
-		case msg := <-c.messages:
-			c.logger.Info(fmt.Sprintf("收到消息 - 主题: %s, 关联ID: %s", msg.ReceivedTopic, msg.CorrelationID))
-
-			if msg.ContentType != "application/json" {
-				c.logger.Error(fmt.Sprintf("无效的内容类型: 收到 %s, 期望 application/json", msg.ContentType))
-				continue
-			}
-
-			var event dtos.Event
-			if err := json.Unmarshal(msg.Payload, &event); err != nil {
-				c.logger.Error(fmt.Sprintf("解析事件失败: %v", err))
-				continue
-			}
-
-			if c.handler != nil {
-				c.handler(msg.ReceivedTopic, event)
-			}
+			c.handleTransportError(err)
 
 		case <-c.stopCh:
 			return
@@ -269,54 +448,79 @@ func (c *MQTTClient) PublishTestEvent(topic string) error {
 		},
 	}
 
-	payload, err := json.Marshal(event)
-	if err != nil {
-		return fmt.Errorf("序列化事件失败: %v", err)
-	}
+	return c.publishEvent(event, topic, c.defaultContentType)
+}
 
-	msgEnvelope := types.MessageEnvelope{
-		CorrelationID: uuid.New().String(),
-		Payload:       payload,
-		ContentType:   "application/json",
-	}
+// PublishEvent 使用客户端默认编码发布自定义事件，断线期间会被缓存到离线队列，重连后按原有顺序补发
+func (c *MQTTClient) PublishEvent(event dtos.Event, topic string) error {
+	return c.publishEvent(event, topic, c.defaultContentType)
+}
 
-	err = c.messageBus.Publish(msgEnvelope, topic)
+// PublishEventWithCodec 发布事件时显式指定编码对应的内容类型（如 application/cbor）
+func (c *MQTTClient) PublishEventWithCodec(event dtos.Event, topic, contentType string) error {
+	return c.publishEvent(event, topic, contentType)
+}
+
+// publishEvent 按给定内容类型选择编解码器编码事件并发布，统一承担离线排队逻辑。
+// go-mod-messaging 的 Publish() 对每条消息都固定使用 [will] 配置的 WillQos/WillRetained
+// （types.MessageEnvelope 本身不带 QoS/Retained 字段），因此这里没有按单次发布区分
+// QoS/保留标志的余地；需要不同的投递语义得换一条连接、单独设置 [will]
+func (c *MQTTClient) publishEvent(event dtos.Event, topic, contentType string) error {
+	codec, err := c.codecFor(contentType)
 	if err != nil {
-		return fmt.Errorf("发布消息失败: %v", err)
+		return err
 	}
 
-	c.logger.Info(fmt.Sprintf("成功发布测试事件到主题 %s, 事件ID: %s", topic, event.Id))
-	return nil
-}
-
-// PublishEvent 发布自定义事件
-func (c *MQTTClient) PublishEvent(event dtos.Event, topic string) error {
-	payload, err := json.Marshal(event)
+	payload, encodedContentType, err := codec.Encode(event)
 	if err != nil {
-		return fmt.Errorf("序列化事件失败: %v", err)
+		return err
 	}
 
 	msgEnvelope := types.MessageEnvelope{
 		CorrelationID: uuid.New().String(),
 		Payload:       payload,
-		ContentType:   "application/json",
+		ContentType:   encodedContentType,
 	}
 
+	if !c.isConnected() {
+		c.enqueueOffline(msgEnvelope, topic)
+		c.metrics.messagesPublishedTotal.WithLabelValues(topic, "queued").Inc()
+		c.logger.Info(fmt.Sprintf("当前离线，事件已加入离线队列，主题 %s, 事件ID: %s", topic, event.Id))
+		return nil
+	}
+
+	start := time.Now()
 	err = c.messageBus.Publish(msgEnvelope, topic)
+	c.metrics.publishLatencySeconds.Observe(time.Since(start).Seconds())
 	if err != nil {
+		c.enqueueOffline(msgEnvelope, topic)
+		c.metrics.messagesPublishedTotal.WithLabelValues(topic, "error").Inc()
+		// Publish 失败视为传输层错误，与 messageErrs 上报的断线走同一条重连路径
+		c.handleTransportError(err)
 		return fmt.Errorf("发布消息失败: %v", err)
 	}
 
-	c.logger.Info(fmt.Sprintf("成功发布事件到主题 %s, 事件ID: %s", topic, event.Id))
+	c.metrics.messagesPublishedTotal.WithLabelValues(topic, "success").Inc()
+	c.logger.Info(fmt.Sprintf("成功发布事件到主题 %s, 事件ID: %s, 内容类型: %s", topic, event.Id, encodedContentType))
 	return nil
 }
 
 // Stop 停止客户端
 func (c *MQTTClient) Stop() {
+	c.notifyListeners(func(l MqttConnListener) { l.OnDisconnecting(c) })
+
+	// close(stopCh) 和 connected 的置位必须在 connMu 锁内完成，与
+	// handleTransportError 的 wg.Add 互斥，避免 wg.Add/wg.Wait 的竞态
+	c.connMu.Lock()
 	close(c.stopCh)
-	if err := c.messageBus.Disconnect(); err != nil {
+	c.connected = false
+	c.connMu.Unlock()
+
+	err := c.messageBus.Disconnect()
+	if err != nil {
 		c.logger.Error(fmt.Sprintf("断开消息总线失败: %v", err))
 	}
+	c.notifyListeners(func(l MqttConnListener) { l.OnDisconnected(c, err) })
 	c.wg.Wait()
 	c.logger.Info("MQTT客户端已停止")
 }
@@ -0,0 +1,176 @@
+package mqttclient
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-messaging/v3/pkg/types"
+)
+
+// handleTransportError 响应传输层错误（来自 messageErrs，或 Publish/Subscribe 直接
+// 返回的错误）：标记断线并触发重连循环。connected 的读写和 wg.Add 必须在同一把
+// connMu 锁内完成，才能和 Stop() 里的 close(stopCh)+wg.Wait() 互斥——否则一次迟到的
+// Add(1) 可能发生在 Wait() 已经把计数归零之后，触发 sync.WaitGroup 的 panic。
+func (c *MQTTClient) handleTransportError(err error) {
+	c.connMu.Lock()
+	if !c.connected {
+		// 已经处于重连流程中，避免重复触发
+		c.connMu.Unlock()
+		return
+	}
+	select {
+	case <-c.stopCh:
+		// 客户端正在/已经停止，不再发起新的重连
+		c.connMu.Unlock()
+		return
+	default:
+	}
+	c.connected = false
+	c.wg.Add(1)
+	c.connMu.Unlock()
+
+	c.notifyListeners(func(l MqttConnListener) { l.OnLostConn(c, err) })
+	go c.reconnectLoop()
+}
+
+// reconnectLoop 按指数退避（带抖动）重试 Connect，并在成功后恢复订阅
+func (c *MQTTClient) reconnectLoop() {
+	defer c.wg.Done()
+
+	interval := time.Duration(c.config.Reconnect.InitialInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Second
+	}
+	maxInterval := time.Duration(c.config.Reconnect.MaxInterval) * time.Second
+	if maxInterval <= 0 {
+		maxInterval = interval
+	}
+
+	for attempt := 1; c.config.Reconnect.MaxAttempts == 0 || attempt <= c.config.Reconnect.MaxAttempts; attempt++ {
+		select {
+		case <-c.stopCh:
+			return
+		default:
+		}
+
+		c.notifyListeners(func(l MqttConnListener) { l.OnReconnecting(c, attempt) })
+		c.logger.Info(fmt.Sprintf("第 %d 次尝试重新连接...", attempt))
+
+		if err := c.messageBus.Connect(); err != nil {
+			c.logger.Error(fmt.Sprintf("重连失败: %v", err))
+
+			wait := withJitter(interval, c.config.Reconnect.Jitter)
+			select {
+			case <-time.After(wait):
+			case <-c.stopCh:
+				return
+			}
+
+			interval *= 2
+			if interval > maxInterval {
+				interval = maxInterval
+			}
+			continue
+		}
+
+		if err := c.resubscribeAll(); err != nil {
+			c.logger.Error(fmt.Sprintf("重连后恢复订阅失败: %v", err))
+			continue
+		}
+
+		c.logger.Info("重连成功，已恢复订阅")
+		c.setConnected(true)
+		c.metrics.reconnectsTotal.Inc()
+		c.notifyListeners(func(l MqttConnListener) { l.OnConnected(c) })
+		c.publishBirthMessage()
+		return
+	}
+
+	c.logger.Error("已达到最大重连次数，放弃重连")
+}
+
+// withJitter 在基础间隔上叠加 ±jitter 比例的随机抖动
+func withJitter(base time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 {
+		return base
+	}
+	delta := float64(base) * jitter
+	offset := (rand.Float64()*2 - 1) * delta
+	result := float64(base) + offset
+	if result < 0 {
+		result = 0
+	}
+	return time.Duration(result)
+}
+
+// enqueueOffline 将待发布消息放入离线队列，按配置的策略处理溢出
+func (c *MQTTClient) enqueueOffline(envelope types.MessageEnvelope, topic string) {
+	pending := pendingPublish{envelope: envelope, topic: topic}
+
+	if c.config.Reconnect.OfflineQueuePolicy == "block" {
+		select {
+		case c.offlineQueue <- pending:
+		case <-c.stopCh:
+		}
+		c.metrics.offlineQueueDepth.Set(float64(len(c.offlineQueue)))
+		return
+	}
+
+	// 默认策略：drop-oldest，队列满时丢弃最旧的一条腾出空间
+	c.queueMu.Lock()
+	defer c.queueMu.Unlock()
+	select {
+	case c.offlineQueue <- pending:
+	default:
+		select {
+		case <-c.offlineQueue:
+			c.logger.Error("离线队列已满，丢弃最旧的消息")
+		default:
+		}
+		select {
+		case c.offlineQueue <- pending:
+		default:
+		}
+	}
+	c.metrics.offlineQueueDepth.Set(float64(len(c.offlineQueue)))
+}
+
+// drainOfflineQueue 在重连成功后按入队顺序补发离线期间缓存的消息
+func (c *MQTTClient) drainOfflineQueue() {
+	defer c.wg.Done()
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case pending := <-c.offlineQueue:
+			c.metrics.offlineQueueDepth.Set(float64(len(c.offlineQueue)))
+			c.flushPending(pending)
+		}
+	}
+}
+
+// flushPending 等待连接恢复后发送一条离线消息，保持 CorrelationID 不变
+func (c *MQTTClient) flushPending(pending pendingPublish) {
+	for {
+		if !c.isConnected() {
+			select {
+			case <-time.After(200 * time.Millisecond):
+				continue
+			case <-c.stopCh:
+				return
+			}
+		}
+
+		if err := c.messageBus.Publish(pending.envelope, pending.topic); err != nil {
+			c.logger.Error(fmt.Sprintf("补发离线消息失败, 关联ID %s: %v", pending.envelope.CorrelationID, err))
+			// 和 publishEvent 一样按传输层错误处理，促使 isConnected() 转为
+			// false，下一轮循环会走上面的退避分支，而不是无退避地忙重试
+			c.handleTransportError(err)
+			continue
+		}
+
+		c.logger.Info(fmt.Sprintf("补发离线消息成功, 主题 %s, 关联ID %s", pending.topic, pending.envelope.CorrelationID))
+		return
+	}
+}
@@ -0,0 +1,30 @@
+package mqttclient
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithJitterZeroOrNegative(t *testing.T) {
+	base := 2 * time.Second
+	for _, jitter := range []float64{0, -0.5} {
+		if got := withJitter(base, jitter); got != base {
+			t.Errorf("withJitter(%v, %v) = %v, want %v unchanged", base, jitter, got, base)
+		}
+	}
+}
+
+// TestWithJitterBounded 验证抖动后的间隔落在 [0, base*(1+jitter)] 范围内，
+// 且结果不会因叠加负向抖动而跌破 0
+func TestWithJitterBounded(t *testing.T) {
+	base := 10 * time.Second
+	jitter := 0.5
+	max := time.Duration(float64(base) * (1 + jitter))
+
+	for i := 0; i < 100; i++ {
+		got := withJitter(base, jitter)
+		if got < 0 || got > max {
+			t.Fatalf("withJitter(%v, %v) = %v, want within [0, %v]", base, jitter, got, max)
+		}
+	}
+}
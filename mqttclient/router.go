@@ -0,0 +1,101 @@
+package mqttclient
+
+import (
+	"strings"
+	"sync"
+)
+
+// topicRoute 关联一个订阅 ID 与它注册的 MQTT 主题过滤器
+type topicRoute struct {
+	subID  string
+	filter string
+}
+
+// topicRouter 维护所有已注册的主题过滤器，按通配符精确度解析出最匹配的订阅
+type topicRouter struct {
+	mu     sync.RWMutex
+	routes []topicRoute
+}
+
+func newTopicRouter() *topicRouter {
+	return &topicRouter{}
+}
+
+// add 注册一个订阅的主题过滤器
+func (r *topicRouter) add(subID, filter string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = append(r.routes, topicRoute{subID: subID, filter: filter})
+}
+
+// remove 注销一个订阅的主题过滤器
+func (r *topicRouter) remove(subID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, route := range r.routes {
+		if route.subID == subID {
+			r.routes = append(r.routes[:i], r.routes[i+1:]...)
+			return
+		}
+	}
+}
+
+// resolve 在所有匹配 topic 的过滤器中，按最长前缀（最少通配符）优先的规则
+// 返回最匹配的订阅 ID；没有任何过滤器匹配时返回空字符串
+func (r *topicRouter) resolve(topic string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	best := ""
+	bestScore := -1
+	for _, route := range r.routes {
+		if !matchTopicFilter(route.filter, topic) {
+			continue
+		}
+		if score := filterSpecificity(route.filter); score > bestScore {
+			bestScore = score
+			best = route.subID
+		}
+	}
+	return best
+}
+
+// matchTopicFilter 按 MQTT 规则判断 topic 是否匹配 filter：
+// "+" 匹配单一层级，"#" 匹配自身及其后所有层级（只能出现在末尾）
+func matchTopicFilter(filter, topic string) bool {
+	filterParts := strings.Split(filter, "/")
+	topicParts := strings.Split(topic, "/")
+
+	for i, fp := range filterParts {
+		if fp == "#" {
+			return true
+		}
+		if i >= len(topicParts) {
+			return false
+		}
+		if fp == "+" {
+			continue
+		}
+		if fp != topicParts[i] {
+			return false
+		}
+	}
+	return len(filterParts) == len(topicParts)
+}
+
+// filterSpecificity 为过滤器打分：字面量层级贡献最多，"+" 其次，"#" 最少，
+// 用于在多个过滤器同时匹配时选出最具体（最长前缀）的一个
+func filterSpecificity(filter string) int {
+	score := 0
+	for _, part := range strings.Split(filter, "/") {
+		switch part {
+		case "#":
+			score += 0
+		case "+":
+			score += 1
+		default:
+			score += 2
+		}
+	}
+	return score
+}
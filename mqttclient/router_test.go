@@ -0,0 +1,67 @@
+package mqttclient
+
+import "testing"
+
+func TestMatchTopicFilter(t *testing.T) {
+	cases := []struct {
+		filter string
+		topic  string
+		want   bool
+	}{
+		{"edgex/events/device1", "edgex/events/device1", true},
+		{"edgex/events/device1", "edgex/events/device2", false},
+		{"edgex/events/+", "edgex/events/device1", true},
+		{"edgex/events/+", "edgex/events/device1/reading1", false},
+		{"edgex/events/#", "edgex/events", true},
+		{"edgex/events/#", "edgex/events/device1/reading1", true},
+		{"edgex/+/device1", "edgex/events/device1", true},
+		{"edgex/+/device1", "edgex/events/device2", false},
+		{"#", "edgex/events/device1", true},
+	}
+
+	for _, tc := range cases {
+		if got := matchTopicFilter(tc.filter, tc.topic); got != tc.want {
+			t.Errorf("matchTopicFilter(%q, %q) = %v, want %v", tc.filter, tc.topic, got, tc.want)
+		}
+	}
+}
+
+func TestFilterSpecificity(t *testing.T) {
+	cases := []struct {
+		filter string
+		want   int
+	}{
+		{"edgex/events/device1", 6},
+		{"edgex/events/+", 5},
+		{"edgex/events/#", 4},
+		{"#", 0},
+	}
+
+	for _, tc := range cases {
+		if got := filterSpecificity(tc.filter); got != tc.want {
+			t.Errorf("filterSpecificity(%q) = %d, want %d", tc.filter, got, tc.want)
+		}
+	}
+}
+
+// TestTopicRouterResolveOverlapping 验证多个过滤器同时匹配同一 topic 时，
+// resolve 按最长前缀（最具体）优先返回对应的订阅 ID
+func TestTopicRouterResolveOverlapping(t *testing.T) {
+	r := newTopicRouter()
+	r.add("wildcard", "edgex/events/#")
+	r.add("plus", "edgex/events/+")
+	r.add("exact", "edgex/events/device1")
+
+	if got := r.resolve("edgex/events/device1"); got != "exact" {
+		t.Errorf("resolve() = %q, want %q", got, "exact")
+	}
+
+	r.remove("exact")
+	if got := r.resolve("edgex/events/device1"); got != "plus" {
+		t.Errorf("resolve() after removing exact = %q, want %q", got, "plus")
+	}
+
+	if got := r.resolve("no/match/here"); got != "" {
+		t.Errorf("resolve() for unmatched topic = %q, want empty", got)
+	}
+}
@@ -0,0 +1,114 @@
+package mqttclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/edgexfoundry/go-mod-messaging/v3/pkg/types"
+	"github.com/google/uuid"
+)
+
+// Request 向 requestTopic 发布一次命令请求，并阻塞等待响应，直至收到回复或
+// ctx/timeout 到期。payload 会被序列化为 JSON 请求体；这让客户端可以驱动 EdgeX
+// 设备命令，而不仅仅是收发事件。请求-响应的配对直接复用 go-mod-messaging
+// MessageClient 内置的 Request 方法——其 DoRequest 实现按 responseTopicPrefix/<RequestID>
+// 匹配回复（RequestID 为空时由库自己生成一个），不是 CorrelationID，
+// HandleRequests 一侧必须按相同的 RequestID 计算响应主题才能配对上。
+func (c *MQTTClient) Request(ctx context.Context, requestTopic, responseTopicPrefix string, payload interface{}, timeout time.Duration) (dtos.Event, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return dtos.Event{}, fmt.Errorf("序列化请求负载失败: %v", err)
+	}
+
+	request := types.MessageEnvelope{
+		CorrelationID: uuid.New().String(),
+		RequestID:     uuid.New().String(),
+		Payload:       body,
+		ContentType:   ContentTypeJSON,
+	}
+
+	type rpcResult struct {
+		reply *types.MessageEnvelope
+		err   error
+	}
+	resultCh := make(chan rpcResult, 1)
+	go func() {
+		reply, err := c.messageBus.Request(request, requestTopic, responseTopicPrefix, timeout)
+		resultCh <- rpcResult{reply, err}
+	}()
+
+	select {
+	case res := <-resultCh:
+		if res.err != nil {
+			return dtos.Event{}, fmt.Errorf("请求失败: %v", res.err)
+		}
+		codec, err := c.codecFor(res.reply.ContentType)
+		if err != nil {
+			return dtos.Event{}, err
+		}
+		return codec.Decode(res.reply.Payload, res.reply.ContentType)
+	case <-ctx.Done():
+		return dtos.Event{}, fmt.Errorf("等待主题 %s 的响应超时", requestTopic)
+	case <-c.stopCh:
+		return dtos.Event{}, fmt.Errorf("客户端已停止")
+	}
+}
+
+// HandleRequests 订阅 topic 上的命令请求，依次调用 fn 处理，并把其返回的 dtos.Event
+// 发布到 responseTopicPrefix/<RequestID>，为 Request 提供服务端实现——这与
+// go-mod-messaging MessageClient.Request（DoRequest）内部等待响应时使用的主题
+// 约定一致，二者都以 RequestID 而非 CorrelationID 来配对。
+func (c *MQTTClient) HandleRequests(topic, responseTopicPrefix string, fn func(dtos.Event) (dtos.Event, error)) (string, error) {
+	return c.subscribeRaw(topic, func(msg types.MessageEnvelope) {
+		c.handleRequestMessage(msg, responseTopicPrefix, fn)
+	})
+}
+
+// handleRequestMessage 解码一条请求消息、执行 fn，并把响应发布回
+// responseTopicPrefix/<RequestID>
+func (c *MQTTClient) handleRequestMessage(msg types.MessageEnvelope, responseTopicPrefix string, fn func(dtos.Event) (dtos.Event, error)) {
+	codec, err := c.codecFor(msg.ContentType)
+	if err != nil {
+		c.logger.Error(err.Error())
+		return
+	}
+
+	request, err := codec.Decode(msg.Payload, msg.ContentType)
+	if err != nil {
+		c.logger.Error(err.Error())
+		return
+	}
+
+	response, err := fn(request)
+	if err != nil {
+		c.logger.Error(fmt.Sprintf("处理请求失败: %v", err))
+		return
+	}
+
+	if msg.RequestID == "" {
+		c.logger.Error("请求消息未携带 RequestID，无法回复")
+		return
+	}
+	responseTopic := strings.TrimRight(responseTopicPrefix, "/") + "/" + msg.RequestID
+
+	replyPayload, contentType, err := codec.Encode(response)
+	if err != nil {
+		c.logger.Error(err.Error())
+		return
+	}
+
+	reply := types.MessageEnvelope{
+		CorrelationID: msg.CorrelationID,
+		RequestID:     msg.RequestID,
+		Payload:       replyPayload,
+		ContentType:   contentType,
+	}
+
+	if err := c.messageBus.Publish(reply, responseTopic); err != nil {
+		c.logger.Error(fmt.Sprintf("发布响应失败: %v", err))
+	}
+}
@@ -0,0 +1,124 @@
+package mqttclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/edgexfoundry/go-mod-messaging/v3/pkg/types"
+	"github.com/google/uuid"
+)
+
+// fakeMessageBus 是 messaging.MessageClient 的内存实现，Request 方法复现
+// go-mod-messaging DoRequest 的真实行为：按 responseTopicPrefix/<RequestID>
+// （而非 CorrelationID）订阅并等待回复，用于验证 RPC 层与这套约定配对正确。
+type fakeMessageBus struct {
+	mu   sync.Mutex
+	subs map[string][]chan types.MessageEnvelope
+}
+
+func newFakeMessageBus() *fakeMessageBus {
+	return &fakeMessageBus{subs: map[string][]chan types.MessageEnvelope{}}
+}
+
+func (f *fakeMessageBus) Connect() error { return nil }
+
+func (f *fakeMessageBus) Publish(message types.MessageEnvelope, topic string) error {
+	f.mu.Lock()
+	chans := append([]chan types.MessageEnvelope{}, f.subs[topic]...)
+	f.mu.Unlock()
+	for _, ch := range chans {
+		ch <- message
+	}
+	return nil
+}
+
+func (f *fakeMessageBus) Subscribe(topics []types.TopicChannel, _ chan error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, t := range topics {
+		f.subs[t.Topic] = append(f.subs[t.Topic], t.Messages)
+	}
+	return nil
+}
+
+func (f *fakeMessageBus) Request(message types.MessageEnvelope, requestTopic, responseTopicPrefix string, timeout time.Duration) (*types.MessageEnvelope, error) {
+	if message.RequestID == "" {
+		message.RequestID = uuid.New().String()
+	}
+	responseTopic := strings.TrimRight(responseTopicPrefix, "/") + "/" + message.RequestID
+
+	replyCh := make(chan types.MessageEnvelope, 1)
+	if err := f.Subscribe([]types.TopicChannel{{Topic: responseTopic, Messages: replyCh}}, nil); err != nil {
+		return nil, err
+	}
+	defer f.Unsubscribe(responseTopic)
+
+	if err := f.Publish(message, requestTopic); err != nil {
+		return nil, err
+	}
+
+	select {
+	case reply := <-replyCh:
+		return &reply, nil
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("timed out waiting for response on %s", responseTopic)
+	}
+}
+
+func (f *fakeMessageBus) Unsubscribe(topics ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, topic := range topics {
+		delete(f.subs, topic)
+	}
+	return nil
+}
+
+func (f *fakeMessageBus) Disconnect() error { return nil }
+
+// newTestClient 搭建一个足以驱动 Subscribe/Request 相关内部状态的最小 MQTTClient，
+// 不经过 NewMQTTClient 的配置文件加载流程
+func newTestClient(bus *fakeMessageBus) *MQTTClient {
+	return &MQTTClient{
+		logger:             logger.NewMockClient(),
+		messageBus:         bus,
+		messageErrs:        make(chan error, 1),
+		stopCh:             make(chan struct{}),
+		subscriptions:      map[string]*subscription{},
+		router:             newTopicRouter(),
+		codecs:             defaultCodecs("test-client"),
+		defaultContentType: ContentTypeJSON,
+	}
+}
+
+// TestRequestHandleRequestsRoundTrip 验证 Request 与 HandleRequests 按
+// responseTopicPrefix/<RequestID> 配对成功，端到端拿到响应
+func TestRequestHandleRequestsRoundTrip(t *testing.T) {
+	bus := newFakeMessageBus()
+	client := newTestClient(bus)
+	defer close(client.stopCh)
+
+	if _, err := client.HandleRequests("edgex/command/request", "edgex/command/response", func(req dtos.Event) (dtos.Event, error) {
+		req.ProfileName = "handled"
+		return req, nil
+	}); err != nil {
+		t.Fatalf("HandleRequests() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	reply, err := client.Request(ctx, "edgex/command/request", "edgex/command/response", map[string]string{"cmd": "ping"}, time.Second)
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+	if reply.ProfileName != "handled" {
+		t.Errorf("Request() reply = %+v, want ProfileName = %q", reply, "handled")
+	}
+}
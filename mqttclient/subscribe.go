@@ -0,0 +1,167 @@
+package mqttclient
+
+import (
+	"fmt"
+
+	"github.com/edgexfoundry/go-mod-messaging/v3/pkg/types"
+	"github.com/google/uuid"
+)
+
+// subscription 记录一个主题订阅及其专属处理函数。rawHandler 供内部需要访问原始
+// types.MessageEnvelope（如 RPC 层读取 CorrelationID）的场景使用，
+// 与面向用户的 handler 互斥。
+type subscription struct {
+	id         string
+	topic      string
+	handler    MessageHandler
+	rawHandler func(types.MessageEnvelope)
+	channel    chan types.MessageEnvelope
+	done       chan struct{}
+}
+
+// subscribeConfigured 按配置订阅主题：优先使用 [[subscriptions]]，否则退回旧版单主题 Topic
+func (c *MQTTClient) subscribeConfigured() error {
+	if len(c.config.Subscriptions) > 0 {
+		for _, sub := range c.config.Subscriptions {
+			if _, err := c.Subscribe(sub.Topic, c.handler); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	_, err := c.Subscribe(c.config.Topic, c.handler)
+	return err
+}
+
+// Subscribe 订阅指定主题并绑定独立的处理函数，返回可用于 Unsubscribe 的订阅 ID。
+// 多个订阅的过滤器存在重叠时，按 MQTT 通配符精确度（最长前缀优先）只分发给最匹配的订阅。
+// 不支持按订阅单独指定 QoS：go-mod-messaging MQTT provider 的 Subscribe() 对每个
+// 主题都固定沿用 [will] 配置的 WillQos，没有按订阅区分的余地
+func (c *MQTTClient) Subscribe(topic string, handler MessageHandler) (string, error) {
+	return c.registerSubscription(topic, handler, nil)
+}
+
+// subscribeRaw 与 Subscribe 类似，但绕过事件解码，直接把原始 types.MessageEnvelope
+// 交给 rawHandler，供 RPC 层等需要 CorrelationID 的内部场景使用。
+func (c *MQTTClient) subscribeRaw(topic string, rawHandler func(types.MessageEnvelope)) (string, error) {
+	return c.registerSubscription(topic, nil, rawHandler)
+}
+
+// registerSubscription 建立到 messageBus 的订阅并启动对应的排空协程
+func (c *MQTTClient) registerSubscription(topic string, handler MessageHandler, rawHandler func(types.MessageEnvelope)) (string, error) {
+	ch := make(chan types.MessageEnvelope)
+	topics := []types.TopicChannel{{Topic: topic, Messages: ch}}
+	if err := c.messageBus.Subscribe(topics, c.messageErrs); err != nil {
+		c.handleTransportError(err)
+		return "", fmt.Errorf("订阅主题 %s 失败: %v", topic, err)
+	}
+
+	sub := &subscription{
+		id:         uuid.New().String(),
+		topic:      topic,
+		handler:    handler,
+		rawHandler: rawHandler,
+		channel:    ch,
+		done:       make(chan struct{}),
+	}
+
+	c.subsMu.Lock()
+	c.subscriptions[sub.id] = sub
+	c.subsMu.Unlock()
+	c.router.add(sub.id, topic)
+
+	c.wg.Add(1)
+	go c.drainSubscription(sub)
+
+	c.logger.Info(fmt.Sprintf("成功订阅主题: %s (ID: %s)", topic, sub.id))
+	return sub.id, nil
+}
+
+// Unsubscribe 取消一个此前通过 Subscribe 建立的订阅
+func (c *MQTTClient) Unsubscribe(subID string) error {
+	c.subsMu.Lock()
+	sub, ok := c.subscriptions[subID]
+	if ok {
+		delete(c.subscriptions, subID)
+	}
+	c.subsMu.Unlock()
+	if !ok {
+		return fmt.Errorf("未找到订阅: %s", subID)
+	}
+
+	c.router.remove(subID)
+	close(sub.done)
+
+	if err := c.messageBus.Unsubscribe(sub.topic); err != nil {
+		return fmt.Errorf("取消订阅 %s 失败: %v", sub.topic, err)
+	}
+	return nil
+}
+
+// resubscribeAll 在重连成功后恢复此前注册的所有订阅
+func (c *MQTTClient) resubscribeAll() error {
+	c.subsMu.Lock()
+	subs := make([]*subscription, 0, len(c.subscriptions))
+	for _, sub := range c.subscriptions {
+		subs = append(subs, sub)
+	}
+	c.subsMu.Unlock()
+
+	for _, sub := range subs {
+		topics := []types.TopicChannel{{Topic: sub.topic, Messages: sub.channel}}
+		if err := c.messageBus.Subscribe(topics, c.messageErrs); err != nil {
+			return fmt.Errorf("重连后恢复订阅 %s 失败: %v", sub.topic, err)
+		}
+	}
+	return nil
+}
+
+// drainSubscription 从订阅专属的 channel 中取出消息并分发
+func (c *MQTTClient) drainSubscription(sub *subscription) {
+	defer c.wg.Done()
+	for {
+		select {
+		case msg := <-sub.channel:
+			if sub.rawHandler != nil {
+				sub.rawHandler(msg)
+			} else {
+				c.dispatchMessage(sub, msg)
+			}
+		case <-sub.done:
+			return
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
+// dispatchMessage 解析消息并调用匹配该订阅的处理函数；若存在过滤器重叠，
+// 仅由 router 判定出的最匹配订阅执行分发，避免重复处理同一条消息
+func (c *MQTTClient) dispatchMessage(sub *subscription, msg types.MessageEnvelope) {
+	if winner := c.router.resolve(msg.ReceivedTopic); winner != "" && winner != sub.id {
+		return
+	}
+
+	c.logger.Info(fmt.Sprintf("收到消息 - 主题: %s, 关联ID: %s, 内容类型: %s", msg.ReceivedTopic, msg.CorrelationID, msg.ContentType))
+
+	codec, err := c.codecFor(msg.ContentType)
+	if err != nil {
+		c.metrics.handlerErrorsTotal.Inc()
+		c.logger.Error(err.Error())
+		return
+	}
+
+	event, err := codec.Decode(msg.Payload, msg.ContentType)
+	if err != nil {
+		c.metrics.handlerErrorsTotal.Inc()
+		c.logger.Error(err.Error())
+		return
+	}
+
+	c.metrics.messagesReceivedTotal.WithLabelValues(msg.ReceivedTopic).Inc()
+
+	if sub.handler != nil {
+		sub.handler(msg.ReceivedTopic, event)
+	}
+}
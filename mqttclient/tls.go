@@ -0,0 +1,119 @@
+package mqttclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/edgexfoundry/go-mod-messaging/v3/pkg/types"
+)
+
+// tlsProtocols 是需要启用 TLS 的 broker.protocol 取值
+var tlsProtocols = map[string]bool{
+	"ssl": true,
+	"tls": true,
+	"wss": true,
+}
+
+// applyTLSOptions 根据 Config.TLS 构建 *tls.Config 做早期校验，
+// 并将 go-mod-messaging MQTT provider 能识别的键写入 busConfig.Optional。
+//
+// go-mod-messaging 实际消费的是它自己的 pkg.TlsConfigurationOptions（只有
+// SkipCertVerify/CertFile/KeyFile/CaFile 等几个字段，没有 ServerName，也不支持
+// 配置 MinVersion），且只在 broker.protocol 属于其 TlsSchemes 允许列表
+// （tcps/ssl/tls/redis/nats，不含 wss）时才会据此构建握手用的 tls.Config。
+// tls.min_version/tls.server_name 在这个版本的依赖下必然不会生效，
+// broker.protocol=wss 时证书相关选项也会被直接忽略，与其让使用方误以为这些
+// 配置是起作用的，这里直接拒绝该配置。
+func applyTLSOptions(cfg *Config, busConfig *types.MessageBusConfig) error {
+	if !tlsProtocols[cfg.Broker.Protocol] && cfg.TLS.CAFile == "" && cfg.TLS.CertFile == "" {
+		return nil
+	}
+
+	if cfg.TLS.MinVersion != "" {
+		return fmt.Errorf("当前 go-mod-messaging MQTT provider 不支持按连接设置 TLS 最小版本，请勿配置 tls.min_version（将被忽略）")
+	}
+	if cfg.TLS.ServerName != "" {
+		return fmt.Errorf("当前 go-mod-messaging MQTT provider 的 TLS 配置不含 ServerName 字段，tls.server_name 不会生效，请勿配置")
+	}
+	if cfg.Broker.Protocol == "wss" && (cfg.TLS.CAFile != "" || cfg.TLS.CertFile != "" || cfg.TLS.KeyFile != "") {
+		return fmt.Errorf("go-mod-messaging 的 TLS 方案列表不包含 wss，broker.protocol=wss 时 tls.ca_file/cert_file/key_file 不会被底层 provider 使用，请改用 tcps/ssl/tls")
+	}
+
+	if _, err := buildTLSConfig(cfg); err != nil {
+		return err
+	}
+
+	if cfg.TLS.CAFile != "" {
+		busConfig.Optional["CaFile"] = cfg.TLS.CAFile
+	}
+	if cfg.TLS.CertFile != "" {
+		busConfig.Optional["CertFile"] = cfg.TLS.CertFile
+	}
+	if cfg.TLS.KeyFile != "" {
+		busConfig.Optional["KeyFile"] = cfg.TLS.KeyFile
+	}
+	busConfig.Optional["SkipCertVerify"] = strconv.FormatBool(cfg.TLS.InsecureSkipVerify)
+
+	// 证书双向认证可替代用户名/密码鉴权
+	if cfg.TLS.CertFile != "" && cfg.TLS.KeyFile != "" {
+		delete(busConfig.Optional, "Username")
+		delete(busConfig.Optional, "Password")
+	}
+
+	return nil
+}
+
+// buildTLSConfig 根据 Config.TLS 加载 CA/客户端证书，提前暴露配置错误
+func buildTLSConfig(cfg *Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: cfg.TLS.InsecureSkipVerify,
+		ServerName:         cfg.TLS.ServerName,
+	}
+
+	version, err := parseTLSMinVersion(cfg.TLS.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.MinVersion = version
+
+	if cfg.TLS.CAFile != "" {
+		caCert, err := os.ReadFile(cfg.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取 CA 证书失败: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("解析 CA 证书失败: %s", cfg.TLS.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.TLS.CertFile != "" && cfg.TLS.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("加载客户端证书失败: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// parseTLSMinVersion 将配置的版本字符串转换为 crypto/tls 的常量，默认 TLS 1.2
+func parseTLSMinVersion(version string) (uint16, error) {
+	switch version {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("不支持的 TLS 最小版本: %s", version)
+	}
+}
@@ -0,0 +1,76 @@
+package mqttclient
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-messaging/v3/pkg/types"
+)
+
+func TestParseTLSMinVersion(t *testing.T) {
+	cases := []struct {
+		version string
+		want    uint16
+		wantErr bool
+	}{
+		{"", tls.VersionTLS12, false},
+		{"1.0", tls.VersionTLS10, false},
+		{"1.1", tls.VersionTLS11, false},
+		{"1.2", tls.VersionTLS12, false},
+		{"1.3", tls.VersionTLS13, false},
+		{"1.4", 0, true},
+		{"ssl3", 0, true},
+	}
+
+	for _, tc := range cases {
+		got, err := parseTLSMinVersion(tc.version)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("parseTLSMinVersion(%q): error = nil, want error", tc.version)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseTLSMinVersion(%q) error = %v", tc.version, err)
+		}
+		if got != tc.want {
+			t.Errorf("parseTLSMinVersion(%q) = %#x, want %#x", tc.version, got, tc.want)
+		}
+	}
+}
+
+// TestApplyTLSOptionsRejectsUnenforceableSettings 验证 tls.min_version/
+// tls.server_name，以及 broker.protocol=wss 搭配证书选项，都会被直接拒绝——
+// 因为当前 go-mod-messaging MQTT provider 不会真正应用这些配置
+func TestApplyTLSOptionsRejectsUnenforceableSettings(t *testing.T) {
+	newCfg := func() *Config {
+		cfg := &Config{}
+		cfg.Broker.Protocol = "tls"
+		cfg.TLS.CAFile = "ca.pem"
+		return cfg
+	}
+
+	t.Run("min_version", func(t *testing.T) {
+		cfg := newCfg()
+		cfg.TLS.MinVersion = "1.2"
+		if err := applyTLSOptions(cfg, &types.MessageBusConfig{Optional: map[string]string{}}); err == nil {
+			t.Fatal("applyTLSOptions() error = nil, want error for tls.min_version")
+		}
+	})
+
+	t.Run("server_name", func(t *testing.T) {
+		cfg := newCfg()
+		cfg.TLS.ServerName = "broker.example.com"
+		if err := applyTLSOptions(cfg, &types.MessageBusConfig{Optional: map[string]string{}}); err == nil {
+			t.Fatal("applyTLSOptions() error = nil, want error for tls.server_name")
+		}
+	})
+
+	t.Run("wss_with_ca_file", func(t *testing.T) {
+		cfg := newCfg()
+		cfg.Broker.Protocol = "wss"
+		if err := applyTLSOptions(cfg, &types.MessageBusConfig{Optional: map[string]string{}}); err == nil {
+			t.Fatal("applyTLSOptions() error = nil, want error for wss + tls.ca_file")
+		}
+	})
+}
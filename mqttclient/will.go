@@ -0,0 +1,43 @@
+package mqttclient
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/edgexfoundry/go-mod-messaging/v3/pkg/types"
+)
+
+// applyWillOptions 将 [will] 配置翻译为底层 MQTT Provider 识别的遗嘱消息 Optional 键，
+// 由 broker 在客户端异常掉线（未经正常 Disconnect）时代为发布，做法与 applyTLSOptions 一致
+func applyWillOptions(cfg *Config, busConfig *types.MessageBusConfig) {
+	if cfg.Will.Topic == "" {
+		return
+	}
+
+	busConfig.Optional["WillTopic"] = cfg.Will.Topic
+	busConfig.Optional["WillPayload"] = cfg.Will.Payload
+	busConfig.Optional["WillQos"] = strconv.Itoa(int(cfg.Will.QoS))
+	busConfig.Optional["WillRetained"] = strconv.FormatBool(cfg.Will.Retained)
+}
+
+// publishBirthMessage 在连接（含重连）成功后向遗嘱主题发布一条"存活"消息，
+// 与 broker 在异常掉线时代发的遗嘱（"死亡"）消息相互印证，供下游判断客户端在线状态。
+// types.MessageEnvelope 没有 Retained 字段：go-mod-messaging 的 Publish() 对每条消息
+// 都固定沿用 applyWillOptions 写入的 WillRetained，所以这条消息是否保留由 [will] 配置决定，
+// 无法针对这一条单独覆盖
+func (c *MQTTClient) publishBirthMessage() {
+	if c.config.Will.Topic == "" {
+		return
+	}
+
+	envelope := types.MessageEnvelope{
+		Payload:     []byte("online"),
+		ContentType: "text/plain",
+	}
+
+	if err := c.messageBus.Publish(envelope, c.config.Will.Topic); err != nil {
+		c.logger.Error(fmt.Sprintf("发布存活消息失败, 主题 %s: %v", c.config.Will.Topic, err))
+		return
+	}
+	c.logger.Info(fmt.Sprintf("已发布存活消息到主题 %s", c.config.Will.Topic))
+}